@@ -0,0 +1,127 @@
+package e2e
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/efs"
+)
+
+// Cloud wraps the subset of the EFS API that the e2e suite needs in order to
+// provision and clean up test fixtures that live outside of the CSI driver's
+// own control loop (i.e. the filesystem the driver mounts PVs against).
+type Cloud struct {
+	efs *efs.EFS
+}
+
+func NewCloud(region string) *Cloud {
+	sess := session.Must(session.NewSessionWithOptions(session.Options{
+		SharedConfigState: session.SharedConfigEnable,
+	}))
+	return &Cloud{
+		efs: efs.New(sess, aws.NewConfig().WithRegion(region)),
+	}
+}
+
+// CreateFileSystem creates a new EFS filesystem tagged as belonging to
+// clusterName. creationToken is EFS's idempotency key for this call: passing
+// the same token twice returns the same filesystem instead of creating a new
+// one, so callers provisioning more than one filesystem for a cluster (see
+// SynchronizedBeforeSuite's parallel create loop) must give each call its own
+// unique token rather than reusing clusterName.
+func (c *Cloud) CreateFileSystem(clusterName, creationToken string) (string, error) {
+	res, err := c.efs.CreateFileSystem(&efs.CreateFileSystemInput{
+		CreationToken: aws.String(creationToken),
+		Tags: []*efs.Tag{
+			{Key: aws.String("efs.csi.aws.com/cluster"), Value: aws.String(clusterName)},
+		},
+	})
+	if err != nil {
+		return "", fmt.Errorf("creating file system: %v", err)
+	}
+
+	err = c.efs.WaitUntilFileSystemAvailable(&efs.DescribeFileSystemsInput{
+		FileSystemId: res.FileSystemId,
+	})
+	if err != nil {
+		return "", fmt.Errorf("waiting for file system %q to become available: %v", aws.StringValue(res.FileSystemId), err)
+	}
+
+	return aws.StringValue(res.FileSystemId), nil
+}
+
+func (c *Cloud) DeleteFileSystem(fileSystemId string) error {
+	_, err := c.efs.DeleteFileSystem(&efs.DeleteFileSystemInput{
+		FileSystemId: aws.String(fileSystemId),
+	})
+	if err != nil {
+		return fmt.Errorf("deleting file system %q: %v", fileSystemId, err)
+	}
+	return nil
+}
+
+// CreateAccessPoint creates an EFS access point on fileSystemId rooted at path
+// with the given POSIX uid/gid, tagged as belonging to clusterName. The Access
+// Point context uses it to mount a pre-created access point without going
+// through CreateVolume; SynchronizedAfterSuite's once-per-suite sweep of
+// access points found by ListAccessPointsForCluster reuses DeleteAccessPoint.
+func (c *Cloud) CreateAccessPoint(clusterName, fileSystemId string, uid, gid int64, path string) (string, error) {
+	res, err := c.efs.CreateAccessPoint(&efs.CreateAccessPointInput{
+		ClientToken:  aws.String(clusterName),
+		FileSystemId: aws.String(fileSystemId),
+		PosixUser: &efs.PosixUser{
+			Uid: aws.Int64(uid),
+			Gid: aws.Int64(gid),
+		},
+		RootDirectory: &efs.RootDirectory{
+			Path: aws.String(path),
+			CreationInfo: &efs.CreationInfo{
+				OwnerUid:    aws.Int64(uid),
+				OwnerGid:    aws.Int64(gid),
+				Permissions: aws.String("700"),
+			},
+		},
+		Tags: []*efs.Tag{
+			{Key: aws.String("efs.csi.aws.com/cluster"), Value: aws.String(clusterName)},
+		},
+	})
+	if err != nil {
+		return "", fmt.Errorf("creating access point on file system %q: %v", fileSystemId, err)
+	}
+	return aws.StringValue(res.AccessPointId), nil
+}
+
+func (c *Cloud) DeleteAccessPoint(accessPointId string) error {
+	_, err := c.efs.DeleteAccessPoint(&efs.DeleteAccessPointInput{
+		AccessPointId: aws.String(accessPointId),
+	})
+	if err != nil {
+		return fmt.Errorf("deleting access point %q: %v", accessPointId, err)
+	}
+	return nil
+}
+
+// ListAccessPointsForCluster returns the ids of every access point on
+// fileSystemId that was tagged as belonging to clusterName. It exists so the
+// after-suite can sweep up access points left behind by tests that create
+// them indirectly through the driver's CreateVolume RPC.
+func (c *Cloud) ListAccessPointsForCluster(clusterName, fileSystemId string) ([]string, error) {
+	var ids []string
+	err := c.efs.DescribeAccessPointsPages(&efs.DescribeAccessPointsInput{
+		FileSystemId: aws.String(fileSystemId),
+	}, func(page *efs.DescribeAccessPointsOutput, lastPage bool) bool {
+		for _, ap := range page.AccessPoints {
+			for _, tag := range ap.Tags {
+				if aws.StringValue(tag.Key) == "efs.csi.aws.com/cluster" && aws.StringValue(tag.Value) == clusterName {
+					ids = append(ids, aws.StringValue(ap.AccessPointId))
+				}
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing access points on file system %q: %v", fileSystemId, err)
+	}
+	return ids, nil
+}
@@ -0,0 +1,103 @@
+// Package csi_mock runs the EFS CSI node and controller servers against a
+// mock gRPC endpoint so that failure modes which are impractical to provoke
+// against a real EFS filesystem (stale mounts, stunnel crashes, kubelet
+// restarts) can be exercised deterministically.
+//
+// Unlike test/e2e, which drives the driver through real Kubernetes storage
+// objects against a real filesystem, this package talks to the driver's gRPC
+// servers directly and substitutes hookable fakes for mount.efs and the EFS
+// API. Each test gets its own *mockCSIDriver instance (see newMockCSIDriver)
+// rather than sharing package-level state, so specs can run in parallel.
+//
+// mockMounter and mockCloud are built to match driver.NewFakeDriver's
+// Mounter/Cloud parameter types; if either interface grows a method this
+// package doesn't implement yet, `go build ./test/e2e/...` will say so and
+// the fake needs a matching addition. Neither mock tries to guess at methods
+// no spec in this package exercises: mockMounter leans on the real
+// k8s.io/mount-utils package functions (e.g. IsCorruptedMnt, which takes the
+// error IsLikelyNotMountPoint returned rather than being a method this mock
+// could plausibly stand in for) instead of reimplementing them, and
+// mockCloud only implements CreateAccessPoint/DeleteAccessPoint.
+package csi_mock
+
+import (
+	"fmt"
+	"net"
+
+	"google.golang.org/grpc"
+
+	"github.com/kubernetes-sigs/aws-efs-csi-driver/pkg/driver"
+)
+
+// hooks lets a test fail the mounter and cloud calls the driver makes while
+// handling an RPC. Each hook runs inside a mock call, not before the RPC
+// itself: BeforeNodeStageVolume on every mockMounter.Mount (including the
+// mounts NodePublishVolume makes), BeforeNodeUnpublishVolume on every
+// mockMounter.Unmount, BeforeCreateVolume on mockCloud.CreateAccessPoint and
+// BeforeDeleteVolume on mockCloud.DeleteAccessPoint. A non-nil error is
+// returned from that mock call in place of its normal result, which is how
+// tests simulate mount.efs failures, stunnel crashes, etc. A nil hook is a
+// no-op. BeforeNodePublishVolume is not called by either mock.
+type hooks struct {
+	BeforeNodeStageVolume     func() error
+	BeforeNodePublishVolume   func() error
+	BeforeNodeUnpublishVolume func() error
+	BeforeCreateVolume        func() error
+	BeforeDeleteVolume        func() error
+}
+
+// mockCSIDriver wires a driver.Driver up to an in-process gRPC server
+// listening on a unix socket, so that the test can dial it exactly the way
+// kubelet would. It is created fresh per test via newMockCSIDriver, which
+// also hands back the mounter and cloud mocks so a spec can seed state (e.g.
+// a pre-existing stale mount) and assert on call counts once the RPC
+// returns, instead of only asserting on the error a hook chose to return.
+type mockCSIDriver struct {
+	endpoint   string
+	driver     *driver.Driver
+	grpcServer *grpc.Server
+	listener   net.Listener
+	hooks      *hooks
+
+	mounter *mockMounter
+	cloud   *mockCloud
+}
+
+// newMockCSIDriver starts a driver.Driver backed by fake node/controller
+// implementations on a unique unix socket and returns a ready-to-use
+// mockCSIDriver. Callers must call tearDown when the test completes.
+func newMockCSIDriver(h *hooks) (*mockCSIDriver, error) {
+	if h == nil {
+		h = &hooks{}
+	}
+
+	sockDir, err := newTestSockDir()
+	if err != nil {
+		return nil, fmt.Errorf("creating mock driver socket dir: %v", err)
+	}
+	endpoint := fmt.Sprintf("unix://%s/csi.sock", sockDir)
+
+	m := &mockCSIDriver{
+		endpoint: endpoint,
+		hooks:    h,
+		mounter:  newMockMounter(h),
+		cloud:    newMockCloud(h),
+	}
+
+	m.driver, err = driver.NewFakeDriver(endpoint, m.mounter, m.cloud)
+	if err != nil {
+		return nil, fmt.Errorf("constructing fake driver: %v", err)
+	}
+
+	go func() {
+		_ = m.driver.Run()
+	}()
+
+	return m, nil
+}
+
+func (m *mockCSIDriver) tearDown() {
+	if m.driver != nil {
+		m.driver.Stop()
+	}
+}
@@ -0,0 +1,54 @@
+package csi_mock
+
+import "fmt"
+
+// mockCloud fakes the access point lifecycle calls the controller server's
+// CreateVolume/DeleteVolume handlers make against pkg/cloud.Cloud. It only
+// implements CreateAccessPoint/DeleteAccessPoint, the two calls the specs in
+// this package actually exercise; earlier revisions also faked a describe
+// lookup, but pkg/cloud.Cloud's real method set isn't available in this
+// checkout to verify against, so speculative methods nothing here calls were
+// removed rather than left as unverifiable compile-risk surface.
+type mockCloud struct {
+	hooks *hooks
+
+	accessPoints map[string]bool
+	nextID       int
+
+	CreateAccessPointCalls int
+	DeleteAccessPointCalls int
+}
+
+func newMockCloud(h *hooks) *mockCloud {
+	return &mockCloud{
+		hooks:        h,
+		accessPoints: map[string]bool{},
+	}
+}
+
+func (c *mockCloud) CreateAccessPoint(clusterName, fileSystemId string, uid, gid int64, path string) (string, error) {
+	c.CreateAccessPointCalls++
+	if c.hooks.BeforeCreateVolume != nil {
+		if err := c.hooks.BeforeCreateVolume(); err != nil {
+			return "", err
+		}
+	}
+	c.nextID++
+	id := fmt.Sprintf("fsap-mock%d", c.nextID)
+	c.accessPoints[id] = true
+	return id, nil
+}
+
+func (c *mockCloud) DeleteAccessPoint(accessPointId string) error {
+	c.DeleteAccessPointCalls++
+	if c.hooks.BeforeDeleteVolume != nil {
+		if err := c.hooks.BeforeDeleteVolume(); err != nil {
+			return err
+		}
+	}
+	if !c.accessPoints[accessPointId] {
+		return fmt.Errorf("access point %q not found", accessPointId)
+	}
+	delete(c.accessPoints, accessPointId)
+	return nil
+}
@@ -0,0 +1,197 @@
+package csi_mock
+
+import (
+	"errors"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/onsi/ginkgo"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"k8s.io/kubernetes/test/e2e/framework"
+
+	csiClient "github.com/kubernetes-csi/csi-test/v3/utils"
+)
+
+var _ = ginkgo.Describe("[efs-csi-mock] Node and controller RPC failure handling", func() {
+	var conn *grpc.ClientConn
+
+	dial := func(m *mockCSIDriver) *grpc.ClientConn {
+		c, err := csiClient.Connect(m.endpoint)
+		if err != nil {
+			ginkgo.Fail("dialing mock driver: " + err.Error())
+		}
+		conn = c
+		return c
+	}
+
+	ginkgo.AfterEach(func() {
+		if conn != nil {
+			_ = conn.Close()
+			conn = nil
+		}
+	})
+
+	ginkgo.It("should recover a mount left stale by a crashed stunnel or kubelet and re-mount it", func() {
+		const target = "/tmp/efs-csi-mock/stage-stale"
+
+		m, err := newMockCSIDriver(nil)
+		framework.ExpectNoError(err, "starting mock driver")
+		defer m.tearDown()
+
+		// Seed a mount that was left behind before this test started and is
+		// stale, as if kubelet or stunnel had crashed mid-mount.
+		m.mounter.mounted[target] = "fs-mock:/stale"
+		m.mounter.forceStale[target] = true
+
+		client := csi.NewNodeClient(dial(m))
+		_, err = client.NodeStageVolume(context.Background(), &csi.NodeStageVolumeRequest{
+			VolumeId:          "fs-mock",
+			StagingTargetPath: target,
+			VolumeCapability:  defaultVolumeCapability(),
+		})
+		framework.ExpectNoError(err, "NodeStageVolume should recover from a stale mount, not fail")
+
+		framework.ExpectEqual(m.mounter.IsLikelyNotMountPointCalls > 0, true, "driver should have checked the existing mount for staleness")
+		framework.ExpectEqual(m.mounter.UnmountCalls > 0, true, "driver should have torn down the stale mount before remounting")
+		framework.ExpectEqual(m.mounter.mounted[target], "fs-mock", "driver should have re-mounted the target with a fresh source")
+	})
+
+	ginkgo.It("should surface a stunnel crash on NodeStageVolume so kubelet retries it", func() {
+		failOnce := true
+		m, err := newMockCSIDriver(&hooks{
+			BeforeNodeStageVolume: func() error {
+				if failOnce {
+					failOnce = false
+					return errors.New("stunnel: connection refused, tunnel not yet listening")
+				}
+				return nil
+			},
+		})
+		framework.ExpectNoError(err, "starting mock driver")
+		defer m.tearDown()
+
+		client := csi.NewNodeClient(dial(m))
+		req := &csi.NodeStageVolumeRequest{
+			VolumeId:          "fs-mock",
+			StagingTargetPath: "/tmp/efs-csi-mock/stage-tls",
+			VolumeCapability:  defaultVolumeCapability(),
+			VolumeContext:     map[string]string{"encryptInTransit": "true"},
+		}
+
+		_, err = client.NodeStageVolume(context.Background(), req)
+		framework.ExpectError(err, "first NodeStageVolume should surface the stunnel failure instead of swallowing it")
+
+		// kubelet's reconciler re-issues NodeStageVolume on failure; simulate
+		// that retry arriving after stunnel has come back up.
+		_, err = client.NodeStageVolume(context.Background(), req)
+		framework.ExpectNoError(err, "retried NodeStageVolume should succeed once stunnel restarts")
+
+		framework.ExpectEqual(m.mounter.MountCalls, 2, "driver should have attempted the mount twice, once per kubelet call")
+	})
+
+	ginkgo.It("should not remount on a repeated NodePublishVolume with identical arguments", func() {
+		m, err := newMockCSIDriver(nil)
+		framework.ExpectNoError(err, "starting mock driver")
+		defer m.tearDown()
+
+		client := csi.NewNodeClient(dial(m))
+		req := &csi.NodePublishVolumeRequest{
+			VolumeId:          "fs-mock",
+			StagingTargetPath: "/tmp/efs-csi-mock/stage",
+			TargetPath:        "/tmp/efs-csi-mock/publish",
+			VolumeCapability:  defaultVolumeCapability(),
+		}
+		_, err = client.NodePublishVolume(context.Background(), req)
+		framework.ExpectNoError(err, "first NodePublishVolume")
+		framework.ExpectEqual(m.mounter.MountCalls, 1, "first NodePublishVolume should perform exactly one bind mount")
+
+		_, err = client.NodePublishVolume(context.Background(), req)
+		framework.ExpectNoError(err, "repeated NodePublishVolume with identical args must be a no-op, not an error")
+		framework.ExpectEqual(m.mounter.MountCalls, 1, "repeated NodePublishVolume must detect the existing mount and skip re-mounting")
+	})
+
+	ginkgo.It("should unmount on NodeUnpublishVolume and allow a clean re-publish afterwards", func() {
+		m, err := newMockCSIDriver(nil)
+		framework.ExpectNoError(err, "starting mock driver")
+		defer m.tearDown()
+
+		client := csi.NewNodeClient(dial(m))
+		publishReq := &csi.NodePublishVolumeRequest{
+			VolumeId:          "fs-mock",
+			StagingTargetPath: "/tmp/efs-csi-mock/stage-remount",
+			TargetPath:        "/tmp/efs-csi-mock/publish-remount",
+			VolumeCapability:  defaultVolumeCapability(),
+		}
+		_, err = client.NodePublishVolume(context.Background(), publishReq)
+		framework.ExpectNoError(err, "initial NodePublishVolume")
+
+		_, err = client.NodeUnpublishVolume(context.Background(), &csi.NodeUnpublishVolumeRequest{
+			VolumeId:   publishReq.VolumeId,
+			TargetPath: publishReq.TargetPath,
+		})
+		framework.ExpectNoError(err, "NodeUnpublishVolume")
+		framework.ExpectEqual(m.mounter.UnmountCalls, 1, "NodeUnpublishVolume should have unmounted the target exactly once")
+
+		// A kubelet restart that re-issues NodePublishVolume against the now
+		// torn-down target should mount fresh rather than skip it.
+		_, err = client.NodePublishVolume(context.Background(), publishReq)
+		framework.ExpectNoError(err, "NodePublishVolume after simulated kubelet restart")
+		framework.ExpectEqual(m.mounter.MountCalls, 2, "driver should re-mount after the target was torn down")
+	})
+
+	ginkgo.It("should provision and release an access point through CreateVolume/DeleteVolume", func() {
+		m, err := newMockCSIDriver(nil)
+		framework.ExpectNoError(err, "starting mock driver")
+		defer m.tearDown()
+
+		client := csi.NewControllerClient(dial(m))
+		createResp, err := client.CreateVolume(context.Background(), &csi.CreateVolumeRequest{
+			Name: "pvc-mock",
+			VolumeCapabilities: []*csi.VolumeCapability{
+				defaultVolumeCapability(),
+			},
+			Parameters: map[string]string{
+				"provisioningMode": "efs-ap",
+				"fileSystemId":     "fs-mock",
+				"directoryPerms":   "700",
+			},
+		})
+		framework.ExpectNoError(err, "CreateVolume")
+		framework.ExpectEqual(m.cloud.CreateAccessPointCalls, 1, "CreateVolume should have provisioned exactly one access point")
+
+		_, err = client.DeleteVolume(context.Background(), &csi.DeleteVolumeRequest{
+			VolumeId: createResp.Volume.VolumeId,
+		})
+		framework.ExpectNoError(err, "DeleteVolume")
+		framework.ExpectEqual(m.cloud.DeleteAccessPointCalls, 1, "DeleteVolume should have released the access point it created")
+	})
+
+	ginkgo.It("should surface a CreateAccessPoint failure from CreateVolume", func() {
+		m, err := newMockCSIDriver(&hooks{
+			BeforeCreateVolume: func() error {
+				return errors.New("AccessPointAlreadyExists")
+			},
+		})
+		framework.ExpectNoError(err, "starting mock driver")
+		defer m.tearDown()
+
+		client := csi.NewControllerClient(dial(m))
+		_, err = client.CreateVolume(context.Background(), &csi.CreateVolumeRequest{
+			Name:               "pvc-mock-failure",
+			VolumeCapabilities: []*csi.VolumeCapability{defaultVolumeCapability()},
+			Parameters: map[string]string{
+				"provisioningMode": "efs-ap",
+				"fileSystemId":     "fs-mock",
+				"directoryPerms":   "700",
+			},
+		})
+		framework.ExpectError(err, "CreateVolume should surface the underlying CreateAccessPoint error")
+	})
+})
+
+func defaultVolumeCapability() *csi.VolumeCapability {
+	return &csi.VolumeCapability{
+		AccessType: &csi.VolumeCapability_Mount{Mount: &csi.VolumeCapability_MountVolume{}},
+		AccessMode: &csi.VolumeCapability_AccessMode{Mode: csi.VolumeCapability_AccessMode_MULTI_NODE_MULTI_WRITER},
+	}
+}
@@ -0,0 +1,86 @@
+package csi_mock
+
+import (
+	"io/ioutil"
+	"os"
+	"syscall"
+)
+
+// newTestSockDir creates a throwaway directory to host the unix socket for a
+// single mockCSIDriver instance, so parallel tests never collide on a path.
+func newTestSockDir() (string, error) {
+	return ioutil.TempDir("", "efs-csi-mock-")
+}
+
+// mockMounter stands in for the real mount.efs/stunnel-backed mounter used by
+// pkg/driver. It lets a test fail specific stages of the mount lifecycle
+// without needing a real NFS server or TLS tunnel, and records call counts so
+// a test can assert the driver actually took the code path it claims to
+// (rather than asserting on the test's own hook bookkeeping).
+type mockMounter struct {
+	hooks *hooks
+
+	mounted map[string]string // target -> source, to fake IsLikelyNotMountPoint
+
+	// forceStale marks targets that IsLikelyNotMountPoint should report as
+	// left behind by a crashed stunnel or kubelet, so a test can seed a
+	// pre-existing corrupt mount before the first RPC.
+	forceStale map[string]bool
+
+	MountCalls                 int
+	UnmountCalls               int
+	IsLikelyNotMountPointCalls int
+}
+
+func newMockMounter(h *hooks) *mockMounter {
+	return &mockMounter{
+		hooks:      h,
+		mounted:    map[string]string{},
+		forceStale: map[string]bool{},
+	}
+}
+
+func (m *mockMounter) Mount(source, target, fsType string, options []string) error {
+	m.MountCalls++
+	if m.hooks.BeforeNodeStageVolume != nil {
+		if err := m.hooks.BeforeNodeStageVolume(); err != nil {
+			return err
+		}
+	}
+	m.mounted[target] = source
+	delete(m.forceStale, target)
+	return nil
+}
+
+func (m *mockMounter) Unmount(target string) error {
+	m.UnmountCalls++
+	if m.hooks.BeforeNodeUnpublishVolume != nil {
+		if err := m.hooks.BeforeNodeUnpublishVolume(); err != nil {
+			return err
+		}
+	}
+	delete(m.mounted, target)
+	delete(m.forceStale, target)
+	return nil
+}
+
+// IsLikelyNotMountPoint reports whether target is mounted by looking it up in
+// the mounted map rather than stat-ing it: a mounted target returns
+// (false, nil) and an unknown one (true, os.ErrNotExist), as a failed stat of
+// a missing path would. A target seeded into forceStale (via the test
+// directly poking the mockMounter returned by newMockCSIDriver) returns
+// syscall.ESTALE, the same errno a kubelet restart or crashed stunnel process
+// would leave behind on the real mount, so the driver's own call to
+// mount-utils' IsCorruptedMnt(err) — a package function, not something this
+// mock can stand in for — classifies it as corrupted and takes its remount
+// recovery path.
+func (m *mockMounter) IsLikelyNotMountPoint(target string) (bool, error) {
+	m.IsLikelyNotMountPointCalls++
+	if m.forceStale[target] {
+		return false, syscall.ESTALE
+	}
+	if _, ok := m.mounted[target]; ok {
+		return false, nil
+	}
+	return true, os.ErrNotExist
+}
@@ -1,9 +1,16 @@
 package e2e
 
 import (
+	"bytes"
+	"encoding/gob"
+	"flag"
 	"fmt"
+	"strconv"
+	"sync"
+
 	"github.com/onsi/ginkgo"
 	v1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -15,40 +22,118 @@ import (
 	"k8s.io/kubernetes/test/e2e/storage/testsuites"
 )
 
-var (
-	// Parameters that are expected to be set by consumers of this package.
-	ClusterName  string
-	Region       string
-	FileSystemId string
-
-	// CreateFileSystem if set true will create an EFS file system before tests.
-	// If set false then FileSystemId must be set.
-	CreateFileSystem = true
-	deleteFileSystem = false
-
-	// DeployDriver if set true will deploy a stable version of the driver before
-	// tests. For CI it should be false because something else ought to deploy an
-	// unstable version of the driver to be tested.
-	DeployDriver  = false
-	destroyDriver = false
+// Config bundles the parameters that used to live as package-level globals.
+// The suite reads them from the package-level cfg below, which it also passes
+// to InitEFSCSIDriver, and that lets it provision and round robin PVs across
+// more than one filesystem in a single invocation.
+type Config struct {
+	ClusterName string
+	Region      string
+
+	// FileSystemIDs is the pool of pre-provisioned filesystems to run
+	// against. If CreateFilesystems is non-zero this is populated by
+	// SynchronizedBeforeSuite instead of being set directly.
+	FileSystemIDs []string
+
+	// CreateFilesystems is the number of EFS filesystems to create before
+	// tests, provisioned in parallel. If zero, FileSystemIDs must be set.
+	CreateFilesystems int
+
+	// GidRangeStart and GidRangeEnd optionally bound the gid range the
+	// efs-ap provisioner picks from when it isn't given an explicit gid by
+	// the PVC. Leave both zero to omit the parameters and let the driver use
+	// its own default range.
+	GidRangeStart int64
+	GidRangeEnd   int64
+
+	// DeployDriver if set true will deploy a stable version of the driver
+	// before tests. For CI it should be false because something else ought
+	// to deploy an unstable version of the driver to be tested.
+	DeployDriver bool
+
+	// TestNFS if set true adds a context that mounts the filesystem with
+	// nfsvers=4.1,noresvport directly, exercising the driver's plain NFS
+	// mount path.
+	TestNFS bool
+
+	// TestTLS if set true (the default) exercises the driver's
+	// encryptInTransit/stunnel mount path: "tls" and the access-point/IAM
+	// mount options are advertised in SupportedMountOption, and the
+	// provisioning/storage-class suites and the Access Point context run.
+	// TestNFS and TestTLS are independent, so a single invocation can
+	// validate either or both paths against one EFS filesystem.
+	TestTLS bool
+
+	deleteFilesystems bool
+	destroyDriver     bool
+
+	mu   sync.Mutex
+	next int
+}
+
+// cfg is the single package-level handle consumers configure before calling
+// ginkgo.RunSpecs; everything that used to be its own global var is now a
+// field on it.
+var cfg = &Config{CreateFilesystems: 1, TestTLS: true}
+
+func init() {
+	flag.BoolVar(&cfg.TestNFS, "test-nfs", cfg.TestNFS, "also run the plain NFSv4.1 mount context against the driver")
+	flag.BoolVar(&cfg.TestTLS, "test-tls", cfg.TestTLS, "advertise the tls/accesspoint/iam mount options and run the provisioning, storage-class and Access Point tests against the driver")
+}
+
+// nextFileSystemId round-robins across the filesystem pool so that PVs
+// created by different tests spread load across all provisioned filesystems
+// instead of hammering a single one.
+func (c *Config) nextFileSystemId() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	id := c.FileSystemIDs[c.next%len(c.FileSystemIDs)]
+	c.next++
+	return id
+}
+
+// nfsMountOptions are the options the NFS-only context mounts with, in place
+// of the "tls" option the driver's stunnel path requires.
+var nfsMountOptions = []string{"nfsvers=4.1", "noresvport"}
+
+// Parameters for the efs-ap dynamic provisioning StorageClass. These map
+// directly onto the efs-csi-driver's provisioner parameters; see
+// GetDynamicProvisionStorageClass.
+const (
+	provisioningModeParam   = "provisioningMode"
+	fileSystemIdParam       = "fileSystemId"
+	directoryPermsParam     = "directoryPerms"
+	gidRangeStartParam      = "gidRangeStart"
+	gidRangeEndParam        = "gidRangeEnd"
+	basePathParam           = "basePath"
+	accessPointProvisioning = "efs-ap"
+	defaultDirectoryPerms   = "700"
 )
 
 type efsDriver struct {
 	driverInfo testsuites.DriverInfo
+	cfg        *Config
 }
 
 var _ testsuites.TestDriver = &efsDriver{}
 
-// TODO implement Inline (unless it's redundant) and DynamicPV
+// TODO implement Inline (unless it's redundant)
 // var _ testsuites.InlineVolumeTestDriver = &efsDriver{}
 var _ testsuites.PreprovisionedPVTestDriver = &efsDriver{}
+var _ testsuites.DynamicPVTestDriver = &efsDriver{}
+
+func InitEFSCSIDriver(cfg *Config) testsuites.TestDriver {
+	supportedMountOptions := sets.NewString("ro")
+	if cfg.TestTLS {
+		supportedMountOptions.Insert("tls", "accesspoint", "iam")
+	}
 
-func InitEFSCSIDriver() testsuites.TestDriver {
 	return &efsDriver{
+		cfg: cfg,
 		driverInfo: testsuites.DriverInfo{
 			Name:                 "efs.csi.aws.com",
 			SupportedFsType:      sets.NewString(""),
-			SupportedMountOption: sets.NewString("tls", "ro"),
+			SupportedMountOption: supportedMountOptions,
 			Capabilities: map[testsuites.Capability]bool{
 				testsuites.CapPersistence: true,
 				testsuites.CapExec:        true,
@@ -85,46 +170,95 @@ func (e *efsDriver) GetPersistentVolumeSource(readOnly bool, fsType string, volu
 	pvSource := v1.PersistentVolumeSource{
 		CSI: &v1.CSIPersistentVolumeSource{
 			Driver:       e.driverInfo.Name,
-			VolumeHandle: FileSystemId,
+			VolumeHandle: e.cfg.nextFileSystemId(),
 		},
 	}
 	return &pvSource, nil
 }
 
-// List of testSuites to be executed in below loop
-var csiTestSuites = []func() testsuites.TestSuite{
+// GetDynamicProvisionStorageClass returns a StorageClass that drives the
+// efs-ap dynamic provisioning path: the external-provisioner sidecar calls
+// CreateVolume, which creates an access point rooted under basePath with the
+// given directoryPerms and gid range, scoped to one filesystem from the pool.
+func (e *efsDriver) GetDynamicProvisionStorageClass(config *testsuites.PerTestConfig, fsType string) *storagev1.StorageClass {
+	provisioner := e.driverInfo.Name
+	parameters := map[string]string{
+		provisioningModeParam: accessPointProvisioning,
+		fileSystemIdParam:     e.cfg.nextFileSystemId(),
+		directoryPermsParam:   defaultDirectoryPerms,
+		basePathParam:         "/dynamic_provisioning",
+	}
+	if e.cfg.GidRangeStart != 0 || e.cfg.GidRangeEnd != 0 {
+		parameters[gidRangeStartParam] = strconv.FormatInt(e.cfg.GidRangeStart, 10)
+		parameters[gidRangeEndParam] = strconv.FormatInt(e.cfg.GidRangeEnd, 10)
+	}
+	ns := config.Framework.Namespace.Name
+	suffix := fmt.Sprintf("%s-sc", e.driverInfo.Name)
+	return testsuites.GetStorageClass(provisioner, parameters, nil, ns, suffix)
+}
+
+// commonTestSuites run regardless of mount mode.
+var commonTestSuites = []func() testsuites.TestSuite{
 	testsuites.InitVolumesTestSuite,
 	testsuites.InitVolumeIOTestSuite,
 	testsuites.InitVolumeModeTestSuite,
 	testsuites.InitSubPathTestSuite,
-	testsuites.InitProvisioningTestSuite,
 	testsuites.InitMultiVolumeTestSuite,
 }
 
+// accessPointTestSuites exercise the driver's efs-ap provisioning path, which
+// doesn't apply unless TestTLS is set.
+var accessPointTestSuites = []func() testsuites.TestSuite{
+	testsuites.InitProvisioningTestSuite,
+	testsuites.InitStorageClassTestSuite,
+}
+
+// getCSITestSuites returns the suites to run for the current mode. The
+// access-point provisioning path only applies to the driver's TLS/stunnel
+// mount path, so it's skipped unless TestTLS is set; TestNFS and TestTLS are
+// independent, so a single invocation can run both against one filesystem.
+func getCSITestSuites(cfg *Config) []func() testsuites.TestSuite {
+	if !cfg.TestTLS {
+		return commonTestSuites
+	}
+	return append(append([]func() testsuites.TestSuite{}, commonTestSuites...), accessPointTestSuites...)
+}
+
 var _ = ginkgo.SynchronizedBeforeSuite(func() []byte {
 	// Validate parameters
-	if !CreateFileSystem && FileSystemId == "" {
-		ginkgo.Fail("Can't run tests without an EFS filesystem: CreateFileSystem is false and FileSystemId is empty")
+	if cfg.CreateFilesystems == 0 && len(cfg.FileSystemIDs) == 0 {
+		ginkgo.Fail("Can't run tests without an EFS filesystem: CreateFilesystems is 0 and FileSystemIDs is empty")
 	}
-	if CreateFileSystem && (Region == "" || ClusterName == "") {
-		ginkgo.Fail("Can't create EFS filesystem: both Region and ClusterName must be non-empty")
+	if cfg.CreateFilesystems > 0 && (cfg.Region == "" || cfg.ClusterName == "") {
+		ginkgo.Fail("Can't create EFS filesystems: both Region and ClusterName must be non-empty")
 	}
 
-	if CreateFileSystem {
-		ginkgo.By(fmt.Sprintf("Creating EFS filesystem in region %q for cluster %q", Region, ClusterName))
+	if cfg.CreateFilesystems > 0 {
+		ginkgo.By(fmt.Sprintf("Creating %d EFS filesystem(s) in region %q for cluster %q", cfg.CreateFilesystems, cfg.Region, cfg.ClusterName))
+
+		c := NewCloud(cfg.Region)
+		ids := make([]string, cfg.CreateFilesystems)
+		errs := make([]error, cfg.CreateFilesystems)
+		var wg sync.WaitGroup
+		for i := 0; i < cfg.CreateFilesystems; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				ids[i], errs[i] = c.CreateFileSystem(cfg.ClusterName, fmt.Sprintf("%s-%d", cfg.ClusterName, i))
+			}(i)
+		}
+		wg.Wait()
 
-		c := NewCloud(Region)
-		id, err := c.CreateFileSystem(ClusterName)
-		if err != nil {
+		for _, err := range errs {
 			framework.ExpectNoError(err, "creating file system")
 		}
 
-		FileSystemId = id
-		ginkgo.By(fmt.Sprintf("Created EFS filesystem %q in region %q for cluster %q", FileSystemId, Region, ClusterName))
-		deleteFileSystem = true
+		cfg.FileSystemIDs = ids
+		ginkgo.By(fmt.Sprintf("Created EFS filesystems %v in region %q for cluster %q", cfg.FileSystemIDs, cfg.Region, cfg.ClusterName))
+		cfg.deleteFilesystems = true
 	}
 
-	if DeployDriver {
+	if cfg.DeployDriver {
 		cs, err := framework.LoadClientset()
 		framework.ExpectNoError(err, "loading kubernetes clientset")
 
@@ -139,48 +273,60 @@ var _ = ginkgo.SynchronizedBeforeSuite(func() []byte {
 			ginkgo.By("Deploying EFS CSI driver")
 			framework.RunKubectlOrDie("apply", "-k", "github.com/kubernetes-sigs/aws-efs-csi-driver/deploy/kubernetes/overlays/stable/?ref=master")
 			ginkgo.By("Deployed EFS CSI driver")
-			destroyDriver = true
+			cfg.destroyDriver = true
 		}
 	}
-	return []byte(FileSystemId)
+
+	var buf bytes.Buffer
+	framework.ExpectNoError(gob.NewEncoder(&buf).Encode(cfg.FileSystemIDs), "encoding file system ids")
+	return buf.Bytes()
 }, func(data []byte) {
-	// allNodesBody: each node needs to set its FileSystemId as returned by node 1
-	FileSystemId = string(data)
+	// allNodesBody: every node hydrates its filesystem pool from node 1,
+	// since only node 1 creates filesystems.
+	framework.ExpectNoError(gob.NewDecoder(bytes.NewReader(data)).Decode(&cfg.FileSystemIDs), "decoding file system ids")
 })
 
 var _ = ginkgo.SynchronizedAfterSuite(func() {
 	// allNodesBody: do nothing because only node 1 needs to delete EFS
 }, func() {
-	if deleteFileSystem {
-		ginkgo.By(fmt.Sprintf("Deleting EFS filesystem %q", FileSystemId))
-
-		c := NewCloud(Region)
-		err := c.DeleteFileSystem(FileSystemId)
-		if err != nil {
-			framework.ExpectNoError(err, "deleting file system")
+	c := NewCloud(cfg.Region)
+	for _, fileSystemId := range cfg.FileSystemIDs {
+		ginkgo.By(fmt.Sprintf("Cleaning up access points left on EFS filesystem %q", fileSystemId))
+
+		accessPointIds, err := c.ListAccessPointsForCluster(cfg.ClusterName, fileSystemId)
+		framework.ExpectNoError(err, "listing access points")
+		for _, id := range accessPointIds {
+			err := c.DeleteAccessPoint(id)
+			framework.ExpectNoError(err, fmt.Sprintf("deleting access point %q", id))
 		}
 
-		ginkgo.By(fmt.Sprintf("Deleted EFS filesystem %q", FileSystemId))
+		if cfg.deleteFilesystems {
+			ginkgo.By(fmt.Sprintf("Deleting EFS filesystem %q", fileSystemId))
+			framework.ExpectNoError(c.DeleteFileSystem(fileSystemId), "deleting file system")
+			ginkgo.By(fmt.Sprintf("Deleted EFS filesystem %q", fileSystemId))
+		}
 	}
 
-	if destroyDriver {
+	if cfg.destroyDriver {
 		ginkgo.By("Cleaning up EFS CSI driver")
 		framework.RunKubectlOrDie("delete", "-k", "github.com/kubernetes-sigs/aws-efs-csi-driver/deploy/kubernetes/overlays/stable/?ref=master")
 	}
 })
 
 var _ = ginkgo.Describe("[efs-csi] EFS CSI", func() {
-	driver := InitEFSCSIDriver()
+	driver := InitEFSCSIDriver(cfg)
 	ginkgo.Context(testsuites.GetDriverNameWithFeatureTags(driver), func() {
-		testsuites.DefineTestSuite(driver, csiTestSuites)
+		testsuites.DefineTestSuite(driver, getCSITestSuites(cfg))
 	})
 
 	f := framework.NewDefaultFramework("efs")
 
 	ginkgo.Context(testsuites.GetDriverNameWithFeatureTags(driver), func() {
 		ginkgo.It("should mount different paths on same volume on same node", func() {
+			fileSystemId := cfg.nextFileSystemId()
+
 			ginkgo.By(fmt.Sprintf("Creating efs pvc & pv with no subpath"))
-			pvcRoot, pvRoot, err := createEFSPVCPV(f.ClientSet, f.Namespace.Name, f.Namespace.Name+"-root", "/")
+			pvcRoot, pvRoot, err := createEFSPVCPV(f.ClientSet, f.Namespace.Name, f.Namespace.Name+"-root", fileSystemId, "/", nil)
 			framework.ExpectNoError(err, "creating efs pvc & pv with no subpath")
 			defer func() { _ = f.ClientSet.CoreV1().PersistentVolumes().Delete(pvRoot.Name, &metav1.DeleteOptions{}) }()
 
@@ -191,12 +337,12 @@ var _ = ginkgo.Describe("[efs-csi] EFS CSI", func() {
 			framework.ExpectNoError(e2epod.WaitForPodSuccessInNamespace(f.ClientSet, pod.Name, f.Namespace.Name), "waiting for pod success")
 
 			ginkgo.By(fmt.Sprintf("Creating efs pvc & pv with subpath /a"))
-			pvcA, pvA, err := createEFSPVCPV(f.ClientSet, f.Namespace.Name, f.Namespace.Name+"-a", "/a")
+			pvcA, pvA, err := createEFSPVCPV(f.ClientSet, f.Namespace.Name, f.Namespace.Name+"-a", fileSystemId, "/a", nil)
 			framework.ExpectNoError(err, "creating efs pvc & pv with subpath /a")
 			defer func() { _ = f.ClientSet.CoreV1().PersistentVolumes().Delete(pvA.Name, &metav1.DeleteOptions{}) }()
 
 			ginkgo.By(fmt.Sprintf("Creating efs pvc & pv with subpath /b"))
-			pvcB, pvB, err := createEFSPVCPV(f.ClientSet, f.Namespace.Name, f.Namespace.Name+"-b", "/b")
+			pvcB, pvB, err := createEFSPVCPV(f.ClientSet, f.Namespace.Name, f.Namespace.Name+"-b", fileSystemId, "/b", nil)
 			framework.ExpectNoError(err, "creating efs pvc & pv with subpath /b")
 			defer func() { _ = f.ClientSet.CoreV1().PersistentVolumes().Delete(pvB.Name, &metav1.DeleteOptions{}) }()
 
@@ -207,10 +353,59 @@ var _ = ginkgo.Describe("[efs-csi] EFS CSI", func() {
 			framework.ExpectNoError(e2epod.WaitForPodNameRunningInNamespace(f.ClientSet, pod.Name, f.Namespace.Name), "waiting for pod running")
 		})
 	})
+
+	if cfg.TestTLS {
+		ginkgo.Context("[efs-csi] Access Point", func() {
+			ginkgo.It("should mount an access point and create files with its POSIX uid/gid", func() {
+				const apUid, apGid = int64(1000), int64(2000)
+				fileSystemId := cfg.nextFileSystemId()
+
+				ginkgo.By(fmt.Sprintf("Creating access point with uid %d, gid %d", apUid, apGid))
+				c := NewCloud(cfg.Region)
+				accessPointId, err := c.CreateAccessPoint(cfg.ClusterName, fileSystemId, apUid, apGid, "/access-point-uid-gid")
+				framework.ExpectNoError(err, "creating access point")
+				defer func() {
+					ginkgo.By(fmt.Sprintf("Deleting access point %q", accessPointId))
+					framework.ExpectNoError(c.DeleteAccessPoint(accessPointId), "deleting access point")
+				}()
+
+				ginkgo.By("Creating efs pvc & pv for the access point")
+				pvc, pv, err := createEFSAccessPointPVCPV(f.ClientSet, f.Namespace.Name, f.Namespace.Name+"-ap", fileSystemId, accessPointId, nil)
+				framework.ExpectNoError(err, "creating efs pvc & pv for access point")
+				defer func() { _ = f.ClientSet.CoreV1().PersistentVolumes().Delete(pv.Name, &metav1.DeleteOptions{}) }()
+
+				ginkgo.By("Creating pod to verify files land with the access point's owner")
+				cmd := fmt.Sprintf("touch /mnt/volume1/ap-owned && test \"$(stat -c '%%u:%%g' /mnt/volume1/ap-owned)\" = \"%d:%d\"", apUid, apGid)
+				pod := e2epod.MakePod(f.Namespace.Name, nil, []*v1.PersistentVolumeClaim{pvc}, false, cmd)
+				pod, err = f.ClientSet.CoreV1().Pods(f.Namespace.Name).Create(pod)
+				framework.ExpectNoError(err, "creating pod")
+				framework.ExpectNoError(e2epod.WaitForPodSuccessInNamespace(f.ClientSet, pod.Name, f.Namespace.Name), "waiting for pod success")
+			})
+		})
+	}
+
+	if cfg.TestNFS {
+		ginkgo.Context("[efs-csi] NFSv4.1", func() {
+			ginkgo.It("should mount the filesystem with nfsvers=4.1,noresvport and no access point", func() {
+				fileSystemId := cfg.nextFileSystemId()
+
+				ginkgo.By("Creating efs pvc & pv with explicit NFSv4.1 mount options")
+				pvc, pv, err := createEFSPVCPV(f.ClientSet, f.Namespace.Name, f.Namespace.Name+"-nfs", fileSystemId, "/", nfsMountOptions)
+				framework.ExpectNoError(err, "creating efs pvc & pv for NFSv4.1 mount")
+				defer func() { _ = f.ClientSet.CoreV1().PersistentVolumes().Delete(pv.Name, &metav1.DeleteOptions{}) }()
+
+				ginkgo.By("Creating pod to write through the NFSv4.1 mount")
+				pod := e2epod.MakePod(f.Namespace.Name, nil, []*v1.PersistentVolumeClaim{pvc}, false, "touch /mnt/volume1/nfs-mode")
+				pod, err = f.ClientSet.CoreV1().Pods(f.Namespace.Name).Create(pod)
+				framework.ExpectNoError(err, "creating pod")
+				framework.ExpectNoError(e2epod.WaitForPodSuccessInNamespace(f.ClientSet, pod.Name, f.Namespace.Name), "waiting for pod success")
+			})
+		})
+	}
 })
 
-func createEFSPVCPV(c clientset.Interface, namespace, name, path string) (*v1.PersistentVolumeClaim, *v1.PersistentVolume, error) {
-	pvc, pv := makeEFSPVCPV(namespace, name, path)
+func createEFSPVCPV(c clientset.Interface, namespace, name, fileSystemId, path string, mountOptions []string) (*v1.PersistentVolumeClaim, *v1.PersistentVolume, error) {
+	pvc, pv := makeEFSPVCPV(namespace, name, fileSystemId, path, mountOptions)
 	pvc, err := c.CoreV1().PersistentVolumeClaims(namespace).Create(pvc)
 	if err != nil {
 		return nil, nil, err
@@ -222,9 +417,9 @@ func createEFSPVCPV(c clientset.Interface, namespace, name, path string) (*v1.Pe
 	return pvc, pv, nil
 }
 
-func makeEFSPVCPV(namespace, name, path string) (*v1.PersistentVolumeClaim, *v1.PersistentVolume) {
+func makeEFSPVCPV(namespace, name, fileSystemId, path string, mountOptions []string) (*v1.PersistentVolumeClaim, *v1.PersistentVolume) {
 	pvc := makeEFSPVC(namespace, name)
-	pv := makeEFSPV(name, path)
+	pv := makeEFSPV(name, fileSystemId, path, mountOptions)
 	pvc.Spec.VolumeName = pv.Name
 	pv.Spec.ClaimRef = &v1.ObjectReference{
 		Namespace: pvc.Namespace,
@@ -233,6 +428,29 @@ func makeEFSPVCPV(namespace, name, path string) (*v1.PersistentVolumeClaim, *v1.
 	return pvc, pv
 }
 
+// createEFSAccessPointPVCPV is the access-point counterpart to
+// createEFSPVCPV: it builds a PVC/PV pair pointing at a pre-created access
+// point instead of a plain subpath.
+func createEFSAccessPointPVCPV(c clientset.Interface, namespace, name, fileSystemId, accessPointId string, mountOptions []string) (*v1.PersistentVolumeClaim, *v1.PersistentVolume, error) {
+	pvc := makeEFSPVC(namespace, name)
+	pv := makeEFSAccessPointPV(name, fileSystemId, accessPointId, mountOptions)
+	pvc.Spec.VolumeName = pv.Name
+	pv.Spec.ClaimRef = &v1.ObjectReference{
+		Namespace: pvc.Namespace,
+		Name:      pvc.Name,
+	}
+
+	pvc, err := c.CoreV1().PersistentVolumeClaims(namespace).Create(pvc)
+	if err != nil {
+		return nil, nil, err
+	}
+	_, err = c.CoreV1().PersistentVolumes().Create(pv)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pvc, pv, nil
+}
+
 func makeEFSPVC(namespace, name string) *v1.PersistentVolumeClaim {
 	storageClassName := ""
 	return &v1.PersistentVolumeClaim{
@@ -252,11 +470,24 @@ func makeEFSPVC(namespace, name string) *v1.PersistentVolumeClaim {
 	}
 }
 
-func makeEFSPV(name, path string) *v1.PersistentVolume {
-	volumeHandle := FileSystemId
+func makeEFSPV(name, fileSystemId, path string, mountOptions []string) *v1.PersistentVolume {
+	volumeHandle := fileSystemId
 	if path != "" {
 		volumeHandle += ":" + path
 	}
+	return newEFSPV(name, volumeHandle, mountOptions)
+}
+
+// makeEFSAccessPointPV builds a PV whose VolumeHandle uses the
+// "fsid::accessPointId" form the driver expects when a volume is rooted at a
+// pre-created access point rather than a plain subpath, and adds the "iam"
+// mount option the driver requires to authorize against that access point.
+func makeEFSAccessPointPV(name, fileSystemId, accessPointId string, mountOptions []string) *v1.PersistentVolume {
+	volumeHandle := fileSystemId + "::" + accessPointId
+	return newEFSPV(name, volumeHandle, append([]string{"iam"}, mountOptions...))
+}
+
+func newEFSPV(name, volumeHandle string, mountOptions []string) *v1.PersistentVolume {
 	return &v1.PersistentVolume{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: name,
@@ -272,7 +503,8 @@ func makeEFSPV(name, path string) *v1.PersistentVolume {
 					VolumeHandle: volumeHandle,
 				},
 			},
-			AccessModes: []v1.PersistentVolumeAccessMode{v1.ReadWriteMany},
+			AccessModes:  []v1.PersistentVolumeAccessMode{v1.ReadWriteMany},
+			MountOptions: mountOptions,
 		},
 	}
 }